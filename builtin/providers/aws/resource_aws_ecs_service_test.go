@@ -0,0 +1,110 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccAWSEcsService_basic(t *testing.T) {
+	var service ecs.Service
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAWSEcsServiceDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccAWSEcsServiceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAWSEcsServiceExists("aws_ecs_service.mongo", &service),
+					resource.TestCheckResourceAttr("aws_ecs_service.mongo", "desired_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAWSEcsServiceDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*AWSClient).ecsconn
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "aws_ecs_service" {
+			continue
+		}
+
+		out, err := conn.DescribeServices(&ecs.DescribeServicesInput{
+			Services: []*string{aws.String(rs.Primary.ID)},
+			Cluster:  aws.String(rs.Primary.Attributes["cluster"]),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, s := range out.Services {
+			if *s.Status != "INACTIVE" {
+				return fmt.Errorf("ECS service still active: %s", *s.ServiceARN)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckAWSEcsServiceExists(name string, service *ecs.Service) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		conn := testAccProvider.Meta().(*AWSClient).ecsconn
+		out, err := conn.DescribeServices(&ecs.DescribeServicesInput{
+			Services: []*string{aws.String(rs.Primary.ID)},
+			Cluster:  aws.String(rs.Primary.Attributes["cluster"]),
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(out.Services) < 1 {
+			return fmt.Errorf("ECS service not found: %s", rs.Primary.ID)
+		}
+
+		*service = *out.Services[0]
+
+		return nil
+	}
+}
+
+const testAccAWSEcsServiceConfig = `
+resource "aws_ecs_cluster" "default" {
+	name = "terraform-acc-test"
+}
+
+resource "aws_ecs_task_definition" "mongo" {
+	family = "mongodb"
+	container_definitions = <<DEFINITION
+[
+	{
+		"cpu": 128,
+		"essential": true,
+		"image": "mongo:latest",
+		"memory": 128,
+		"name": "mongodb"
+	}
+]
+DEFINITION
+}
+
+resource "aws_ecs_service" "mongo" {
+	name            = "mongodb"
+	cluster         = "${aws_ecs_cluster.default.id}"
+	task_definition = "${aws_ecs_task_definition.mongo.arn}"
+	desired_count   = 2
+}
+`
@@ -2,8 +2,6 @@ package aws
 
 import (
 	"bytes"
-	"crypto/sha1"
-	"encoding/hex"
 	"fmt"
 	"log"
 
@@ -14,6 +12,9 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+// task_role_arn and network_mode are not exposed here: the vendored
+// aws-sdk-go does not yet have RegisterTaskDefinitionInput/TaskDefinition
+// fields for either, so there's nothing to wire them to.
 func resourceAwsEcsTaskDefinition() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAwsEcsTaskDefinitionCreate,
@@ -39,11 +40,161 @@ func resourceAwsEcsTaskDefinition() *schema.Resource {
 			},
 
 			"container_definitions": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
+				Type:       schema.TypeString,
+				Optional:   true,
+				Deprecated: "Use container_definition blocks instead. This attribute will be removed in a future release.",
 				StateFunc: func(v interface{}) string {
-					hash := sha1.Sum([]byte(v.(string)))
-					return hex.EncodeToString(hash[:])
+					normalized, err := normalizeEcsContainerDefinitionsJSON(v.(string))
+					if err != nil {
+						// Leave the raw value in place so the user sees their
+						// own invalid JSON reflected back, rather than an error
+						// from a StateFunc, which cannot return one.
+						return v.(string)
+					}
+					return normalized
+				},
+			},
+
+			"container_definition": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"image": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+
+						"cpu": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+
+						"memory": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+
+						"essential": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+
+						"command": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"entry_point": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"environment": &schema.Schema{
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"port_mappings": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"container_port": &schema.Schema{
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+
+									"host_port": &schema.Schema{
+										Type:     schema.TypeInt,
+										Optional: true,
+									},
+
+									"protocol": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  "tcp",
+									},
+								},
+							},
+						},
+
+						"mount_points": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"source_volume": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"container_path": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"read_only": &schema.Schema{
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+								},
+							},
+						},
+
+						"volumes_from": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"source_container": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"read_only": &schema.Schema{
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+								},
+							},
+						},
+
+						"links": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+
+						"log_configuration": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"log_driver": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+
+									"options": &schema.Schema{
+										Type:     schema.TypeMap,
+										Optional: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 
@@ -73,7 +224,8 @@ func resourceAwsEcsTaskDefinitionCreate(d *schema.ResourceData, meta interface{}
 	conn := meta.(*AWSClient).ecsconn
 
 	rawDefinitions := d.Get("container_definitions").(string)
-	definitions, err := expandEcsContainerDefinitions(rawDefinitions)
+	configuredDefinitions := d.Get("container_definition").([]interface{})
+	definitions, err := expandEcsContainerDefinitions(rawDefinitions, configuredDefinitions)
 	if err != nil {
 		return err
 	}
@@ -126,9 +278,22 @@ func resourceAwsEcsTaskDefinitionRead(d *schema.ResourceData, meta interface{})
 	d.Set("arn", *taskDefinition.TaskDefinitionARN)
 	d.Set("family", *taskDefinition.Family)
 	d.Set("revision", *taskDefinition.Revision)
-	d.Set("container_definitions", taskDefinition.ContainerDefinitions)
 	d.Set("volumes", flattenEcsVolumes(taskDefinition.Volumes))
 
+	// When container_definition blocks are set they take precedence over
+	// container_definitions, as in expandEcsContainerDefinitions; only refresh
+	// the attribute in use so we don't fabricate drift against one the user
+	// never populated.
+	if _, ok := d.GetOk("container_definition"); ok {
+		d.Set("container_definition", flattenEcsContainerDefinitions(taskDefinition.ContainerDefinitions))
+	} else {
+		canonicalJSON, err := canonicalizeEcsContainerDefinitions(taskDefinition.ContainerDefinitions)
+		if err != nil {
+			return err
+		}
+		d.Set("container_definitions", canonicalJSON)
+	}
+
 	return nil
 }
 
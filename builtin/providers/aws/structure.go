@@ -0,0 +1,538 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+// expandEcsContainerDefinitions builds the list of container definitions to
+// register a task definition with. It accepts either the structured
+// container_definition blocks or, for backwards compatibility, the raw
+// container_definitions JSON string. The structured form takes precedence
+// when both are set.
+func expandEcsContainerDefinitions(rawDefinitions string, configuredDefinitions []interface{}) ([]*ecs.ContainerDefinition, error) {
+	if len(configuredDefinitions) > 0 {
+		return expandEcsContainerDefinitionBlocks(configuredDefinitions)
+	}
+
+	var definitions []*ecs.ContainerDefinition
+	err := json.Unmarshal([]byte(rawDefinitions), &definitions)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding container_definitions: %s", err)
+	}
+
+	if err := validateEcsContainerDefinitions(definitions); err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
+func expandEcsContainerDefinitionBlocks(configured []interface{}) ([]*ecs.ContainerDefinition, error) {
+	definitions := make([]*ecs.ContainerDefinition, 0, len(configured))
+
+	for _, rawDefinition := range configured {
+		data := rawDefinition.(map[string]interface{})
+
+		def := &ecs.ContainerDefinition{
+			Name:      aws.String(data["name"].(string)),
+			Image:     aws.String(data["image"].(string)),
+			Memory:    aws.Long(int64(data["memory"].(int))),
+			Essential: aws.Boolean(data["essential"].(bool)),
+		}
+
+		if v, ok := data["cpu"]; ok {
+			def.CPU = aws.Long(int64(v.(int)))
+		}
+
+		if v, ok := data["command"]; ok {
+			def.Command = expandStringList(v.([]interface{}))
+		}
+
+		if v, ok := data["entry_point"]; ok {
+			def.EntryPoint = expandStringList(v.([]interface{}))
+		}
+
+		if v, ok := data["links"]; ok {
+			def.Links = expandStringList(v.([]interface{}))
+		}
+
+		if v, ok := data["environment"]; ok {
+			def.Environment = expandEcsKeyValuePairs(v.(map[string]interface{}))
+		}
+
+		if v, ok := data["port_mappings"]; ok {
+			def.PortMappings = expandEcsPortMappings(v.([]interface{}))
+		}
+
+		if v, ok := data["mount_points"]; ok {
+			def.MountPoints = expandEcsMountPoints(v.([]interface{}))
+		}
+
+		if v, ok := data["volumes_from"]; ok {
+			def.VolumesFrom = expandEcsVolumesFrom(v.([]interface{}))
+		}
+
+		if v, ok := data["log_configuration"]; ok {
+			configs := v.([]interface{})
+			if len(configs) > 0 {
+				logConfig := configs[0].(map[string]interface{})
+				def.LogConfiguration = &ecs.LogConfiguration{
+					LogDriver: aws.String(logConfig["log_driver"].(string)),
+					Options:   expandEcsStringMap(logConfig["options"].(map[string]interface{})),
+				}
+			}
+		}
+
+		definitions = append(definitions, def)
+	}
+
+	if err := validateEcsContainerDefinitions(definitions); err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
+// validateEcsContainerDefinitions enforces the constraints the ECS API
+// itself imposes on a task definition's containers, so config errors surface
+// with a clear message at apply time, before the registration call, instead
+// of as an opaque API error. It is not run during plan.
+func validateEcsContainerDefinitions(definitions []*ecs.ContainerDefinition) error {
+	hasEssential := false
+
+	for _, def := range definitions {
+		if def.Memory != nil && *def.Memory <= 4 {
+			return fmt.Errorf("Error validating container_definitions: memory for container %q must be greater than 4", *def.Name)
+		}
+
+		// ECS defaults essential to true when a container omits it, so a nil
+		// Essential here (e.g. from the raw JSON form) must count as essential,
+		// not as "not set".
+		if def.Essential == nil || *def.Essential {
+			hasEssential = true
+		}
+	}
+
+	if !hasEssential {
+		return fmt.Errorf("Error validating container_definitions: at least one container must be essential")
+	}
+
+	return nil
+}
+
+func expandEcsPortMappings(configured []interface{}) []*ecs.PortMapping {
+	mappings := make([]*ecs.PortMapping, 0, len(configured))
+
+	for _, rawMapping := range configured {
+		data := rawMapping.(map[string]interface{})
+
+		mapping := &ecs.PortMapping{
+			ContainerPort: aws.Long(int64(data["container_port"].(int))),
+			Protocol:      aws.String(data["protocol"].(string)),
+		}
+
+		if v, ok := data["host_port"]; ok {
+			mapping.HostPort = aws.Long(int64(v.(int)))
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings
+}
+
+func expandEcsMountPoints(configured []interface{}) []*ecs.MountPoint {
+	mountPoints := make([]*ecs.MountPoint, 0, len(configured))
+
+	for _, rawMountPoint := range configured {
+		data := rawMountPoint.(map[string]interface{})
+
+		mountPoints = append(mountPoints, &ecs.MountPoint{
+			SourceVolume:  aws.String(data["source_volume"].(string)),
+			ContainerPath: aws.String(data["container_path"].(string)),
+			ReadOnly:      aws.Boolean(data["read_only"].(bool)),
+		})
+	}
+
+	return mountPoints
+}
+
+func expandEcsVolumesFrom(configured []interface{}) []*ecs.VolumeFrom {
+	volumesFrom := make([]*ecs.VolumeFrom, 0, len(configured))
+
+	for _, rawVolumeFrom := range configured {
+		data := rawVolumeFrom.(map[string]interface{})
+
+		volumesFrom = append(volumesFrom, &ecs.VolumeFrom{
+			SourceContainer: aws.String(data["source_container"].(string)),
+			ReadOnly:        aws.Boolean(data["read_only"].(bool)),
+		})
+	}
+
+	return volumesFrom
+}
+
+func expandEcsKeyValuePairs(configured map[string]interface{}) []*ecs.KeyValuePair {
+	pairs := make([]*ecs.KeyValuePair, 0, len(configured))
+
+	for k, v := range configured {
+		pairs = append(pairs, &ecs.KeyValuePair{
+			Name:  aws.String(k),
+			Value: aws.String(v.(string)),
+		})
+	}
+
+	return pairs
+}
+
+func expandEcsStringMap(configured map[string]interface{}) map[string]*string {
+	result := make(map[string]*string, len(configured))
+
+	for k, v := range configured {
+		result[k] = aws.String(v.(string))
+	}
+
+	return result
+}
+
+func expandStringList(configured []interface{}) []*string {
+	result := make([]*string, 0, len(configured))
+
+	for _, v := range configured {
+		result = append(result, aws.String(v.(string)))
+	}
+
+	return result
+}
+
+func expandEcsVolumes(configured []interface{}) ([]*ecs.Volume, error) {
+	volumes := make([]*ecs.Volume, 0, len(configured))
+
+	for _, rawVolume := range configured {
+		data := rawVolume.(map[string]interface{})
+
+		volumes = append(volumes, &ecs.Volume{
+			Name: aws.String(data["name"].(string)),
+			Host: &ecs.HostVolumeProperties{
+				SourcePath: aws.String(data["host_path"].(string)),
+			},
+		})
+	}
+
+	return volumes, nil
+}
+
+// canonicalizeEcsContainerDefinitions converts the ECS API's representation
+// of a task definition's containers into the same lowercase-camelCase JSON
+// shape users write container_definitions in (the SDK struct has no `json`
+// tags, so marshaling it directly produces PascalCase Go field names
+// instead), then normalizes it the same way normalizeEcsContainerDefinitionsJSON
+// does. This keeps both sides comparable so a `terraform plan` reflects only
+// meaningful drift (image tags, env vars, port mappings, etc.), not
+// formatting or server-filled defaults.
+func canonicalizeEcsContainerDefinitions(defs []*ecs.ContainerDefinition) (string, error) {
+	raw := make([]map[string]interface{}, 0, len(defs))
+	for _, def := range defs {
+		raw = append(raw, ecsContainerDefinitionToJSONMap(def))
+	}
+
+	return normalizeEcsContainerDefinitionsMaps(raw)
+}
+
+// normalizeEcsContainerDefinitionsJSON parses a container_definitions JSON
+// string and re-serializes it with sorted keys, consistent int/bool types,
+// and server-filled defaults (cpu: 0, essential: true) stripped so it can be
+// compared directly against the live task definition's container list.
+func normalizeEcsContainerDefinitionsJSON(rawJSON string) (string, error) {
+	var raw []map[string]interface{}
+	if err := json.Unmarshal([]byte(rawJSON), &raw); err != nil {
+		return "", fmt.Errorf("Error decoding container_definitions: %s", err)
+	}
+
+	return normalizeEcsContainerDefinitionsMaps(raw)
+}
+
+func normalizeEcsContainerDefinitionsMaps(raw []map[string]interface{}) (string, error) {
+	for _, def := range raw {
+		if cpu, ok := def["cpu"]; ok {
+			if f, ok := cpu.(float64); ok && f == 0 {
+				delete(def, "cpu")
+			}
+		}
+
+		if essential, ok := def["essential"]; ok {
+			if b, ok := essential.(bool); ok && b {
+				delete(def, "essential")
+			}
+		}
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("Error encoding container_definitions: %s", err)
+	}
+
+	return string(normalized), nil
+}
+
+// ecsContainerDefinitionToJSONMap mirrors the real ECS container definition
+// JSON schema (lowercase-camelCase keys, environment/portMappings/etc. as
+// AWS documents them) so it normalizes identically to hand-written
+// container_definitions JSON.
+func ecsContainerDefinitionToJSONMap(def *ecs.ContainerDefinition) map[string]interface{} {
+	m := map[string]interface{}{}
+
+	if def.Name != nil {
+		m["name"] = *def.Name
+	}
+	if def.Image != nil {
+		m["image"] = *def.Image
+	}
+	if def.CPU != nil {
+		m["cpu"] = float64(*def.CPU)
+	}
+	if def.Memory != nil {
+		m["memory"] = float64(*def.Memory)
+	}
+	if def.Essential != nil {
+		m["essential"] = *def.Essential
+	}
+	if len(def.Command) > 0 {
+		m["command"] = flattenStringList(def.Command)
+	}
+	if len(def.EntryPoint) > 0 {
+		m["entryPoint"] = flattenStringList(def.EntryPoint)
+	}
+	if len(def.Links) > 0 {
+		m["links"] = flattenStringList(def.Links)
+	}
+
+	if len(def.Environment) > 0 {
+		env := make([]interface{}, 0, len(def.Environment))
+		for _, kv := range def.Environment {
+			env = append(env, map[string]interface{}{
+				"name":  *kv.Name,
+				"value": *kv.Value,
+			})
+		}
+		m["environment"] = env
+	}
+
+	if len(def.PortMappings) > 0 {
+		mappings := make([]interface{}, 0, len(def.PortMappings))
+		for _, p := range def.PortMappings {
+			entry := map[string]interface{}{}
+			if p.ContainerPort != nil {
+				entry["containerPort"] = float64(*p.ContainerPort)
+			}
+			if p.HostPort != nil {
+				entry["hostPort"] = float64(*p.HostPort)
+			}
+			if p.Protocol != nil {
+				entry["protocol"] = *p.Protocol
+			}
+			mappings = append(mappings, entry)
+		}
+		m["portMappings"] = mappings
+	}
+
+	if len(def.MountPoints) > 0 {
+		mountPoints := make([]interface{}, 0, len(def.MountPoints))
+		for _, mp := range def.MountPoints {
+			entry := map[string]interface{}{}
+			if mp.SourceVolume != nil {
+				entry["sourceVolume"] = *mp.SourceVolume
+			}
+			if mp.ContainerPath != nil {
+				entry["containerPath"] = *mp.ContainerPath
+			}
+			if mp.ReadOnly != nil {
+				entry["readOnly"] = *mp.ReadOnly
+			}
+			mountPoints = append(mountPoints, entry)
+		}
+		m["mountPoints"] = mountPoints
+	}
+
+	if len(def.VolumesFrom) > 0 {
+		volumesFrom := make([]interface{}, 0, len(def.VolumesFrom))
+		for _, vf := range def.VolumesFrom {
+			entry := map[string]interface{}{}
+			if vf.SourceContainer != nil {
+				entry["sourceContainer"] = *vf.SourceContainer
+			}
+			if vf.ReadOnly != nil {
+				entry["readOnly"] = *vf.ReadOnly
+			}
+			volumesFrom = append(volumesFrom, entry)
+		}
+		m["volumesFrom"] = volumesFrom
+	}
+
+	if def.LogConfiguration != nil {
+		logConfig := map[string]interface{}{}
+		if def.LogConfiguration.LogDriver != nil {
+			logConfig["logDriver"] = *def.LogConfiguration.LogDriver
+		}
+		if len(def.LogConfiguration.Options) > 0 {
+			options := make(map[string]interface{}, len(def.LogConfiguration.Options))
+			for k, v := range def.LogConfiguration.Options {
+				options[k] = *v
+			}
+			logConfig["options"] = options
+		}
+		m["logConfiguration"] = logConfig
+	}
+
+	return m
+}
+
+func flattenEcsContainerDefinitions(defs []*ecs.ContainerDefinition) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(defs))
+
+	for _, def := range defs {
+		m := map[string]interface{}{
+			"name":      *def.Name,
+			"image":     *def.Image,
+			"essential": *def.Essential,
+		}
+
+		if def.CPU != nil {
+			m["cpu"] = int(*def.CPU)
+		}
+		if def.Memory != nil {
+			m["memory"] = int(*def.Memory)
+		}
+		if len(def.Command) > 0 {
+			m["command"] = flattenStringList(def.Command)
+		}
+		if len(def.EntryPoint) > 0 {
+			m["entry_point"] = flattenStringList(def.EntryPoint)
+		}
+		if len(def.Links) > 0 {
+			m["links"] = flattenStringList(def.Links)
+		}
+		if len(def.Environment) > 0 {
+			env := make(map[string]string, len(def.Environment))
+			for _, kv := range def.Environment {
+				env[*kv.Name] = *kv.Value
+			}
+			m["environment"] = env
+		}
+		if len(def.PortMappings) > 0 {
+			m["port_mappings"] = flattenEcsPortMappings(def.PortMappings)
+		}
+		if len(def.MountPoints) > 0 {
+			m["mount_points"] = flattenEcsMountPoints(def.MountPoints)
+		}
+		if len(def.VolumesFrom) > 0 {
+			m["volumes_from"] = flattenEcsVolumesFrom(def.VolumesFrom)
+		}
+		if def.LogConfiguration != nil {
+			m["log_configuration"] = flattenEcsLogConfiguration(def.LogConfiguration)
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+func flattenEcsPortMappings(list []*ecs.PortMapping) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+
+	for _, p := range list {
+		m := map[string]interface{}{
+			"container_port": int(*p.ContainerPort),
+		}
+		if p.HostPort != nil {
+			m["host_port"] = int(*p.HostPort)
+		}
+		if p.Protocol != nil {
+			m["protocol"] = *p.Protocol
+		}
+		result = append(result, m)
+	}
+
+	return result
+}
+
+func flattenEcsMountPoints(list []*ecs.MountPoint) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+
+	for _, mp := range list {
+		m := map[string]interface{}{
+			"source_volume":  *mp.SourceVolume,
+			"container_path": *mp.ContainerPath,
+		}
+		if mp.ReadOnly != nil {
+			m["read_only"] = *mp.ReadOnly
+		}
+		result = append(result, m)
+	}
+
+	return result
+}
+
+func flattenEcsVolumesFrom(list []*ecs.VolumeFrom) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+
+	for _, vf := range list {
+		m := map[string]interface{}{
+			"source_container": *vf.SourceContainer,
+		}
+		if vf.ReadOnly != nil {
+			m["read_only"] = *vf.ReadOnly
+		}
+		result = append(result, m)
+	}
+
+	return result
+}
+
+func flattenEcsLogConfiguration(config *ecs.LogConfiguration) []map[string]interface{} {
+	m := map[string]interface{}{}
+
+	if config.LogDriver != nil {
+		m["log_driver"] = *config.LogDriver
+	}
+	if len(config.Options) > 0 {
+		options := make(map[string]string, len(config.Options))
+		for k, v := range config.Options {
+			options[k] = *v
+		}
+		m["options"] = options
+	}
+
+	return []map[string]interface{}{m}
+}
+
+func flattenStringList(list []*string) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		result = append(result, *v)
+	}
+	return result
+}
+
+func flattenEcsVolumes(list []*ecs.Volume) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(list))
+
+	for _, volume := range list {
+		l := map[string]interface{}{
+			"name": *volume.Name,
+		}
+
+		if volume.Host != nil && volume.Host.SourcePath != nil {
+			l["host_path"] = *volume.Host.SourcePath
+		}
+
+		result = append(result, l)
+	}
+
+	return result
+}
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandEcsContainerDefinitions_jsonForm(t *testing.T) {
+	raw := `[
+		{
+			"name": "mongodb",
+			"image": "mongo:latest",
+			"cpu": 128,
+			"memory": 128,
+			"essential": true
+		}
+	]`
+
+	definitions, err := expandEcsContainerDefinitions(raw, nil)
+	if err != nil {
+		t.Fatalf("expandEcsContainerDefinitions returned error: %s", err)
+	}
+
+	if len(definitions) != 1 {
+		t.Fatalf("expected 1 container definition, got %d", len(definitions))
+	}
+	if *definitions[0].Name != "mongodb" {
+		t.Fatalf("expected name %q, got %q", "mongodb", *definitions[0].Name)
+	}
+}
+
+func TestExpandEcsContainerDefinitions_jsonFormOmittedEssential(t *testing.T) {
+	raw := `[
+		{
+			"name": "mongodb",
+			"image": "mongo:latest",
+			"memory": 128
+		}
+	]`
+
+	if _, err := expandEcsContainerDefinitions(raw, nil); err != nil {
+		t.Fatalf("expected omitted essential to default to true, got error: %s", err)
+	}
+}
+
+func TestExpandEcsContainerDefinitions_blockForm(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{
+			"name":      "mongodb",
+			"image":     "mongo:latest",
+			"cpu":       128,
+			"memory":    128,
+			"essential": true,
+		},
+	}
+
+	definitions, err := expandEcsContainerDefinitions("", configured)
+	if err != nil {
+		t.Fatalf("expandEcsContainerDefinitions returned error: %s", err)
+	}
+
+	if len(definitions) != 1 {
+		t.Fatalf("expected 1 container definition, got %d", len(definitions))
+	}
+	if *definitions[0].Image != "mongo:latest" {
+		t.Fatalf("expected image %q, got %q", "mongo:latest", *definitions[0].Image)
+	}
+}
+
+func TestValidateEcsContainerDefinitions_requiresEssential(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{
+			"name":      "sidecar",
+			"image":     "busybox:latest",
+			"memory":    128,
+			"essential": false,
+		},
+	}
+
+	if _, err := expandEcsContainerDefinitions("", configured); err == nil {
+		t.Fatal("expected error when no container is essential")
+	}
+}
+
+func TestValidateEcsContainerDefinitions_memoryTooLow(t *testing.T) {
+	configured := []interface{}{
+		map[string]interface{}{
+			"name":      "mongodb",
+			"image":     "mongo:latest",
+			"memory":    4,
+			"essential": true,
+		},
+	}
+
+	if _, err := expandEcsContainerDefinitions("", configured); err == nil {
+		t.Fatal("expected error when memory is not greater than 4")
+	}
+}
+
+func TestNormalizeEcsContainerDefinitionsJSON_stripsDefaults(t *testing.T) {
+	raw := `[{"name":"mongodb","image":"mongo:latest","cpu":0,"essential":true,"memory":128}]`
+
+	normalized, err := normalizeEcsContainerDefinitionsJSON(raw)
+	if err != nil {
+		t.Fatalf("normalizeEcsContainerDefinitionsJSON returned error: %s", err)
+	}
+
+	if strings.Contains(normalized, `"cpu"`) {
+		t.Errorf("expected server-filled cpu:0 to be stripped, got: %s", normalized)
+	}
+	if strings.Contains(normalized, `"essential"`) {
+		t.Errorf("expected server-filled essential:true to be stripped, got: %s", normalized)
+	}
+}
+
+func TestCanonicalizeEcsContainerDefinitions_matchesNormalizedJSON(t *testing.T) {
+	raw := `[{"name":"mongodb","image":"mongo:latest","memory":128}]`
+
+	configured, err := expandEcsContainerDefinitions(raw, nil)
+	if err != nil {
+		t.Fatalf("expandEcsContainerDefinitions returned error: %s", err)
+	}
+
+	remoteJSON, err := canonicalizeEcsContainerDefinitions(configured)
+	if err != nil {
+		t.Fatalf("canonicalizeEcsContainerDefinitions returned error: %s", err)
+	}
+
+	configJSON, err := normalizeEcsContainerDefinitionsJSON(raw)
+	if err != nil {
+		t.Fatalf("normalizeEcsContainerDefinitionsJSON returned error: %s", err)
+	}
+
+	if remoteJSON != configJSON {
+		t.Errorf("expected remote and config canonical JSON to match:\nremote: %s\nconfig: %s", remoteJSON, configJSON)
+	}
+}